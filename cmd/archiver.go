@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const archiverPollInterval = time.Duration(secondsPerSlot) * time.Second
+
+// nextSlotToArchive tracks the next not-yet-archived slot, so a slow tick
+// doesn't leave a gap: archiveExpiringSlots always catches up from here
+// rather than only ever looking at the slot currently crossing the
+// retention boundary. It is held in memory only: after a restart archiving
+// starts again at the current boundary, and slots that crossed it while the
+// process was down are not archived.
+var nextSlotToArchive uint64
+
+// runArchiver tails newly-produced slots from the upstream beacon and
+// persists their sidecars to store shortly before they leave the
+// retention window, so handleBlobSidecarsRequest can serve them from the
+// archive instead of falling back to reconstruction or an empty response.
+func runArchiver(store BlobStore) {
+	ticker := time.NewTicker(archiverPollInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		archiveExpiringSlots(store)
+	}
+}
+
+// archiveExpiringSlots archives every slot that has crossed the retention
+// boundary since the last tick, not just the one currently crossing it: a
+// slow tick (slow upstream/S3 put) or downtime between ticks can advance
+// the boundary by more than one slot, and without catching up those slots
+// would be skipped permanently.
+func archiveExpiringSlots(store BlobStore) {
+	curSlot := (uint64(time.Now().Unix()) - slot0Timestamp) / secondsPerSlot
+	windowSlots := retentionPeriod * slotsPerEpoch
+	if curSlot < windowSlots {
+		return
+	}
+	boundary := curSlot - windowSlots
+	if nextSlotToArchive == 0 {
+		nextSlotToArchive = boundary
+	}
+	for slot := nextSlotToArchive; slot <= boundary; slot++ {
+		archiveSlot(store, slot)
+	}
+	nextSlotToArchive = boundary + 1
+}
+
+func archiveSlot(store BlobStore, slot uint64) {
+	if store.Has(slot) {
+		return
+	}
+	sidecars, err := fetchSidecarsFromUpstream(slot)
+	if err != nil {
+		logger.Error("Archiver: failed to fetch sidecars", "slot", slot, "err", err)
+		return
+	}
+	// Persist confirmed-empty slots too (sidecars may be nil/empty here),
+	// so Has reports true for them going forward and future requests are
+	// served straight from the archive instead of falling through to EL
+	// reconstruction on every single request for a slot that legitimately
+	// never had any blobs.
+	if err := store.Put(slot, sidecars); err != nil {
+		logger.Error("Archiver: failed to persist sidecars", "slot", slot, "err", err)
+		return
+	}
+	logger.Info("Archiver: persisted blob sidecars", "slot", slot, "count", len(sidecars))
+}
+
+func fetchSidecarsFromUpstream(slot uint64) ([]*BlobSidecar, error) {
+	resp, err := beaconPool.Get("/eth/v1/beacon/blob_sidecars/" + strconv.FormatUint(slot, 10))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	// upstreamPool.Get only fails over on a connection error or 5xx; a
+	// non-5xx error status (e.g. 404 for a slot the upstream won't serve)
+	// still reaches here and must not be treated as "no blobs", since the
+	// archiver persists whatever it gets back as a confirmed-empty entry.
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching slot %d sidecars: upstream returned %s", slot, resp.Status)
+	}
+	response := new(APIGetBlobSidecarsResponse)
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
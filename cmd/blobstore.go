@@ -0,0 +1,14 @@
+package main
+
+// BlobStore persists blob sidecars beyond the upstream beacon's retention
+// window so they can be served without falling back to EL reconstruction.
+// Implementations must be safe for concurrent use.
+type BlobStore interface {
+	// Put persists the sidecars for a slot, overwriting any existing entry.
+	Put(slot uint64, sidecars []*BlobSidecar) error
+	// Get returns the sidecars stored for a slot, filtered to indices when
+	// non-nil. It returns an error if the slot has not been archived.
+	Get(slot uint64, indices []int) ([]*BlobSidecar, error)
+	// Has reports whether a slot has been archived.
+	Has(slot uint64) bool
+}
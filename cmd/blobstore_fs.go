@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// fsBlobStore is a BlobStore backed by the local filesystem, one JSON file
+// per slot named "<slot>.json".
+type fsBlobStore struct {
+	dir string
+}
+
+func newFSBlobStore(dir string) (*fsBlobStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating archive dir %s: %w", dir, err)
+	}
+	return &fsBlobStore{dir: dir}, nil
+}
+
+func (s *fsBlobStore) path(slot uint64) string {
+	return filepath.Join(s.dir, strconv.FormatUint(slot, 10)+".json")
+}
+
+func (s *fsBlobStore) Put(slot uint64, sidecars []*BlobSidecar) error {
+	data, err := json.Marshal(sidecars)
+	if err != nil {
+		return err
+	}
+	tmp := s.path(slot) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path(slot))
+}
+
+func (s *fsBlobStore) Get(slot uint64, indices []int) ([]*BlobSidecar, error) {
+	data, err := os.ReadFile(s.path(slot))
+	if err != nil {
+		return nil, fmt.Errorf("slot %d is not archived: %w", slot, err)
+	}
+	var sidecars []*BlobSidecar
+	if err := json.Unmarshal(data, &sidecars); err != nil {
+		return nil, err
+	}
+	return filterSidecarsByIndices(sidecars, indices), nil
+}
+
+func (s *fsBlobStore) Has(slot uint64) bool {
+	_, err := os.Stat(s.path(slot))
+	return err == nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// s3BlobStore is a BlobStore backed by an S3-compatible object store, one
+// object per slot under "<slot>.json".
+type s3BlobStore struct {
+	client *s3.Client
+	bucket string
+}
+
+// s3Config collects the flags needed to reach an S3-compatible endpoint.
+type s3Config struct {
+	endpoint  string
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+}
+
+func newS3BlobStore(cfg s3Config) (*s3BlobStore, error) {
+	if cfg.bucket == "" {
+		return nil, fmt.Errorf("archive.s3.bucket is required")
+	}
+
+	opts := []func(*awsconfig.LoadOptions) error{}
+	if cfg.region != "" {
+		opts = append(opts, awsconfig.WithRegion(cfg.region))
+	}
+	if cfg.accessKey != "" {
+		opts = append(opts, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(cfg.accessKey, cfg.secretKey, ""),
+		))
+	}
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("loading AWS config: %w", err)
+	}
+
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if cfg.endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.endpoint)
+			o.UsePathStyle = true
+		}
+	})
+
+	return &s3BlobStore{client: client, bucket: cfg.bucket}, nil
+}
+
+func (s *s3BlobStore) key(slot uint64) string {
+	return strconv.FormatUint(slot, 10) + ".json"
+}
+
+func (s *s3BlobStore) Put(slot uint64, sidecars []*BlobSidecar) error {
+	data, err := json.Marshal(sidecars)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(slot)),
+		Body:   bytes.NewReader(data),
+	})
+	return err
+}
+
+func (s *s3BlobStore) Get(slot uint64, indices []int) ([]*BlobSidecar, error) {
+	out, err := s.client.GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(slot)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("slot %d is not archived: %w", slot, err)
+	}
+	defer out.Body.Close()
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, err
+	}
+	var sidecars []*BlobSidecar
+	if err := json.Unmarshal(data, &sidecars); err != nil {
+		return nil, err
+	}
+	return filterSidecarsByIndices(sidecars, indices), nil
+}
+
+func (s *s3BlobStore) Has(slot uint64) bool {
+	_, err := s.client.HeadObject(context.Background(), &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(slot)),
+	})
+	return err == nil
+}
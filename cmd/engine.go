@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// engineClient is an authenticated JSON-RPC client for the execution
+// layer's Engine API, used to fall back to engine_getBlobsV1 when the
+// upstream beacon has no sidecars for a recent slot (e.g. still syncing).
+type engineClient struct {
+	endpoint  string
+	jwtSecret []byte
+}
+
+func newEngineClient(endpoint, jwtSecretPath string) (*engineClient, error) {
+	raw, err := os.ReadFile(jwtSecretPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading engine JWT secret: %w", err)
+	}
+	secret, err := hex.DecodeString(strings.TrimSpace(strings.TrimPrefix(string(raw), "0x")))
+	if err != nil {
+		return nil, fmt.Errorf("decoding engine JWT secret: %w", err)
+	}
+	return &engineClient{endpoint: endpoint, jwtSecret: secret}, nil
+}
+
+// authToken mints a short-lived JWT per the Engine API authentication spec:
+// HS256, signed with the shared secret, with an `iat` claim the server
+// accepts within a small clock-drift window.
+func (c *engineClient) authToken() (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iat": time.Now().Unix(),
+	})
+	return token.SignedString(c.jwtSecret)
+}
+
+func (c *engineClient) call(method string, params ...interface{}) (json.RawMessage, error) {
+	token, err := c.authToken()
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(elRequest{JSONRPC: "2.0", ID: 1, Method: method, Params: params})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var rpcResp elResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return nil, err
+	}
+	if rpcResp.Error != nil {
+		return nil, fmt.Errorf("%s: %s", method, rpcResp.Error.Message)
+	}
+	return rpcResp.Result, nil
+}
+
+// engineBlobAndProofV1 mirrors the `BlobAndProofV1` object returned by
+// engine_getBlobsV1 for each requested versioned hash, or null if the EL
+// doesn't have it.
+type engineBlobAndProofV1 struct {
+	Blob  string `json:"blob"`
+	Proof string `json:"proof"`
+}
+
+func (c *engineClient) getBlobsV1(versionedHashes []string) ([]*engineBlobAndProofV1, error) {
+	raw, err := c.call("engine_getBlobsV1", versionedHashes)
+	if err != nil {
+		return nil, err
+	}
+	var results []*engineBlobAndProofV1
+	if err := json.Unmarshal(raw, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+type beaconHeaderEnvelope struct {
+	Data struct {
+		Header SignedBeaconBlockHeader `json:"header"`
+	} `json:"data"`
+}
+
+func fetchSignedBlockHeader(slot uint64) (*SignedBeaconBlockHeader, error) {
+	resp, err := beaconPool.Get("/eth/v1/beacon/headers/" + strconv.FormatUint(slot, 10))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	envelope := new(beaconHeaderEnvelope)
+	if err := json.NewDecoder(resp.Body).Decode(envelope); err != nil {
+		return nil, err
+	}
+	return &envelope.Data.Header, nil
+}
+
+// reconstructFromEngine fills in the sidecars for slot from the connected
+// execution client's blob pool, for the case where the upstream beacon is
+// still syncing or has already pruned a slot inside the retention window.
+func (c *engineClient) reconstructFromEngine(slot uint64) ([]*BlobSidecar, error) {
+	block, err := findBlockForSlot(slot)
+	if err != nil {
+		return nil, err
+	}
+	var versionedHashes []string
+	for _, tx := range block.Transactions {
+		if tx.Type == blobTxType {
+			versionedHashes = append(versionedHashes, tx.BlobVersionedHashes...)
+		}
+	}
+	if len(versionedHashes) == 0 {
+		return nil, nil
+	}
+
+	blobsAndProofs, err := c.getBlobsV1(versionedHashes)
+	if err != nil {
+		return nil, fmt.Errorf("engine_getBlobsV1 for slot %d: %w", slot, err)
+	}
+
+	header, err := fetchSignedBlockHeader(slot)
+	if err != nil {
+		return nil, fmt.Errorf("fetching signed block header for slot %d: %w", slot, err)
+	}
+
+	var sidecars []*BlobSidecar
+	for index, bp := range blobsAndProofs {
+		if bp == nil {
+			continue
+		}
+		blobBytes, err := hexToBytes(bp.Blob)
+		if err != nil {
+			return nil, err
+		}
+		var blob kzg4844.Blob
+		copy(blob[:], blobBytes)
+		commitment, err := kzg4844.BlobToCommitment(blob)
+		if err != nil {
+			return nil, fmt.Errorf("computing commitment for slot %d blob %d: %w", slot, index, err)
+		}
+
+		sidecars = append(sidecars, &BlobSidecar{
+			Index:             strconv.Itoa(index),
+			Blob:              bp.Blob,
+			KZGCommitment:     bytesToHex(commitment[:]),
+			KZGProof:          bp.Proof,
+			SignedBlockHeader: header,
+			// KZGCommitmentInclusionProof is set to an explicit empty
+			// slice rather than left nil: a correct proof requires the
+			// real sibling data from the rest of the BeaconBlockBody
+			// container (not just the commitments list, which this
+			// wrapper doesn't merkleize), and a spec-shaped but
+			// cryptographically bogus proof is worse than an absent one
+			// for a consumer that verifies it. See the field's doc
+			// comment on BlobSidecar for the full caveat.
+			KZGCommitmentInclusionProof: []string{},
+		})
+	}
+	return sidecars, nil
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// specError is the `{code, message}` error body shape used throughout the
+// beacon API spec.
+type specError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func writeSpecError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(&specError{Code: code, Message: message})
+}
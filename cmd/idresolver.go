@@ -0,0 +1,137 @@
+package main
+
+import (
+	"container/list"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const rootToSlotCacheSize = 1024
+
+// headersResponse mirrors the subset of GET /eth/v1/beacon/headers/{id}
+// needed to resolve a block ID to its slot.
+type headersResponse struct {
+	Data struct {
+		Header struct {
+			Message struct {
+				Slot string `json:"slot"`
+			} `json:"message"`
+		} `json:"header"`
+	} `json:"data"`
+}
+
+// rootToSlotCache is a small LRU cache mapping a block root to the slot it
+// was resolved to, so repeated lookups for the same root don't round-trip
+// to the upstream beacon node.
+type rootToSlotCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+type rootToSlotEntry struct {
+	root string
+	slot uint64
+}
+
+func newRootToSlotCache(capacity int) *rootToSlotCache {
+	return &rootToSlotCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element, capacity),
+		order:    list.New(),
+	}
+}
+
+func (c *rootToSlotCache) get(root string) (uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[root]
+	if !ok {
+		return 0, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*rootToSlotEntry).slot, true
+}
+
+func (c *rootToSlotCache) put(root string, slot uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[root]; ok {
+		elem.Value.(*rootToSlotEntry).slot = slot
+		c.order.MoveToFront(elem)
+		return
+	}
+	elem := c.order.PushFront(&rootToSlotEntry{root: root, slot: slot})
+	c.entries[root] = elem
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*rootToSlotEntry).root)
+		}
+	}
+}
+
+var rootToSlot = newRootToSlotCache(rootToSlotCacheSize)
+
+// resolveSlot turns a block ID as accepted by the beacon API (a slot
+// number, a 0x-prefixed block root, or one of the named identifiers
+// "head"/"finalized"/"genesis") into a slot number. Roots are resolved via
+// the upstream beacon's headers endpoint and cached; named identifiers are
+// always resolved live since they point at a moving target.
+func resolveSlot(id string) (uint64, error) {
+	if slot, err := strconv.ParseUint(id, 10, 64); err == nil {
+		return slot, nil
+	}
+	if isBlockRoot(id) {
+		if slot, ok := rootToSlot.get(id); ok {
+			return slot, nil
+		}
+	} else if !isKnownIdentifier(id) {
+		return 0, fmt.Errorf("invalid block ID: %s", id)
+	}
+
+	slot, err := fetchSlotForID(id)
+	if err != nil {
+		return 0, err
+	}
+	if isBlockRoot(id) {
+		rootToSlot.put(id, slot)
+	}
+	return slot, nil
+}
+
+// isBlockRoot reports whether id is a 0x-prefixed 32-byte hex block root.
+func isBlockRoot(id string) bool {
+	if len(id) != 66 || !strings.HasPrefix(id, "0x") {
+		return false
+	}
+	_, err := hex.DecodeString(id[2:])
+	return err == nil
+}
+
+func fetchSlotForID(id string) (uint64, error) {
+	resp, err := beaconPool.Get("/eth/v1/beacon/headers/" + id)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("upstream returned %s for headers/%s", resp.Status, id)
+	}
+	headers := new(headersResponse)
+	if err := json.NewDecoder(resp.Body).Decode(headers); err != nil {
+		return 0, err
+	}
+	var slot uint64
+	if _, err := fmt.Sscanf(headers.Data.Header.Message.Slot, "%d", &slot); err != nil {
+		return 0, fmt.Errorf("parsing slot from headers/%s response: %w", id, err)
+	}
+	return slot, nil
+}
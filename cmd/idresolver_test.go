@@ -0,0 +1,45 @@
+package main
+
+import "testing"
+
+func TestRootToSlotCacheGetPut(t *testing.T) {
+	c := newRootToSlotCache(2)
+	c.put("0xa", 1)
+	c.put("0xb", 2)
+	if slot, ok := c.get("0xa"); !ok || slot != 1 {
+		t.Fatalf("get(0xa) = %d, %v; want 1, true", slot, ok)
+	}
+	if _, ok := c.get("0xc"); ok {
+		t.Fatalf("get(0xc) should miss on an unknown root")
+	}
+}
+
+func TestRootToSlotCacheEvictsOldest(t *testing.T) {
+	c := newRootToSlotCache(2)
+	c.put("0xa", 1)
+	c.put("0xb", 2)
+	c.put("0xc", 3)
+	if _, ok := c.get("0xa"); ok {
+		t.Fatalf("0xa should have been evicted once the cache exceeded its capacity")
+	}
+	if _, ok := c.get("0xb"); !ok {
+		t.Fatalf("0xb should still be cached")
+	}
+	if _, ok := c.get("0xc"); !ok {
+		t.Fatalf("0xc should still be cached")
+	}
+}
+
+func TestRootToSlotCacheGetRefreshesRecency(t *testing.T) {
+	c := newRootToSlotCache(2)
+	c.put("0xa", 1)
+	c.put("0xb", 2)
+	c.get("0xa")
+	c.put("0xc", 3)
+	if _, ok := c.get("0xb"); ok {
+		t.Fatalf("0xb should have been evicted as the least recently used entry")
+	}
+	if _, ok := c.get("0xa"); !ok {
+		t.Fatalf("0xa should still be cached after being refreshed by get")
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// maxBlobsPerBlockFallback is used only if queryMaxBlobsPerBlock fails to
+// find a recognized field in /eth/v1/config/spec (e.g. an upstream that
+// predates blob support). It matches the original Deneb value.
+const maxBlobsPerBlockFallback = 6
+
+// specMaxBlobsPerBlockFields lists the /eth/v1/config/spec fields that
+// carry MAX_BLOBS_PER_BLOCK, newest fork first: the value has grown across
+// forks (Electra/Pectra and later blob-parameter-only forks), and the spec
+// endpoint only ever reports the fields for forks the network has
+// activated, so the first one present is the network's current cap.
+var specMaxBlobsPerBlockFields = []string{
+	"MAX_BLOBS_PER_BLOCK_ELECTRA",
+	"MAX_BLOBS_PER_BLOCK",
+}
+
+// maxBlobsPerBlock is the network's current MAX_BLOBS_PER_BLOCK, queried
+// from upstream at startup so index validation tracks mainnet's evolving
+// blob count instead of a value frozen at compile time.
+var maxBlobsPerBlock = maxBlobsPerBlockFallback
+
+type specResponse struct {
+	Data map[string]string `json:"data"`
+}
+
+// queryMaxBlobsPerBlock fetches /eth/v1/config/spec and sets
+// maxBlobsPerBlock from it, falling back to maxBlobsPerBlockFallback (and
+// logging a warning) if the spec response doesn't carry a recognized
+// field.
+func queryMaxBlobsPerBlock() {
+	resp, err := beaconPool.Get(specMethod)
+	if err != nil {
+		logger.Warn("Failed to fetch spec, using fallback MAX_BLOBS_PER_BLOCK", "fallback", maxBlobsPerBlockFallback, "err", err)
+		return
+	}
+	defer resp.Body.Close()
+	spec := new(specResponse)
+	if err := json.NewDecoder(resp.Body).Decode(spec); err != nil {
+		logger.Warn("Failed to parse spec, using fallback MAX_BLOBS_PER_BLOCK", "fallback", maxBlobsPerBlockFallback, "err", err)
+		return
+	}
+	for _, field := range specMaxBlobsPerBlockFields {
+		raw, ok := spec.Data[field]
+		if !ok {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			logger.Warn("Spec field is not an integer, using fallback MAX_BLOBS_PER_BLOCK", "field", field, "value", raw, "fallback", maxBlobsPerBlockFallback)
+			return
+		}
+		maxBlobsPerBlock = value
+		logger.Info("Set MAX_BLOBS_PER_BLOCK from spec", "field", field, "value", value)
+		return
+	}
+	logger.Warn("Spec response has no recognized MAX_BLOBS_PER_BLOCK field, using fallback", "fallback", maxBlobsPerBlockFallback)
+}
+
+// parseIndices parses the `indices` query parameter into a set of blob
+// indices, validating that each is a non-negative integer under
+// maxBlobsPerBlock. A nil slice with a nil error means no filter was
+// requested.
+func parseIndices(r *http.Request) ([]int, error) {
+	raw := r.URL.Query().Get("indices")
+	if raw == "" {
+		return nil, nil
+	}
+	parts := strings.Split(raw, ",")
+	indices := make([]int, 0, len(parts))
+	for _, part := range parts {
+		index, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || index < 0 || index >= maxBlobsPerBlock {
+			return nil, fmt.Errorf("invalid index %q: must be an integer in [0, %d)", part, maxBlobsPerBlock)
+		}
+		indices = append(indices, index)
+	}
+	return indices, nil
+}
+
+// filterSidecarsByIndices returns the subset of sidecars whose Index field
+// matches one of the requested indices. A nil indices slice means no
+// filter was requested, so sidecars is returned unchanged.
+func filterSidecarsByIndices(sidecars []*BlobSidecar, indices []int) []*BlobSidecar {
+	if indices == nil {
+		return sidecars
+	}
+	wanted := make(map[string]bool, len(indices))
+	for _, index := range indices {
+		wanted[strconv.Itoa(index)] = true
+	}
+	filtered := make([]*BlobSidecar, 0, len(sidecars))
+	for _, sidecar := range sidecars {
+		if wanted[sidecar.Index] {
+			filtered = append(filtered, sidecar)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseIndices(t *testing.T) {
+	saved := maxBlobsPerBlock
+	maxBlobsPerBlock = 6
+	defer func() { maxBlobsPerBlock = saved }()
+
+	tests := []struct {
+		name    string
+		query   string
+		want    []int
+		wantErr bool
+	}{
+		{"empty", "", nil, false},
+		{"single", "indices=2", []int{2}, false},
+		{"multiple", "indices=0,3,5", []int{0, 3, 5}, false},
+		{"out of range", "indices=6", nil, true},
+		{"negative", "indices=-1", nil, true},
+		{"not an integer", "indices=foo", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/?"+tt.query, nil)
+			got, err := parseIndices(r)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseIndices() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseIndices() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("parseIndices() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterSidecarsByIndices(t *testing.T) {
+	sidecars := []*BlobSidecar{
+		{Index: "0"},
+		{Index: "1"},
+		{Index: "2"},
+	}
+
+	if got := filterSidecarsByIndices(sidecars, nil); len(got) != 3 {
+		t.Fatalf("nil indices should return all sidecars, got %d", len(got))
+	}
+
+	got := filterSidecarsByIndices(sidecars, []int{0, 2})
+	if len(got) != 2 || got[0].Index != "0" || got[1].Index != "2" {
+		t.Fatalf("filterSidecarsByIndices(_, [0,2]) = %+v, want indices 0 and 2", got)
+	}
+}
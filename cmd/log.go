@@ -0,0 +1,18 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"sync/atomic"
+)
+
+var logger = slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+var requestIDCounter atomic.Uint64
+
+// nextRequestID returns a process-unique, monotonically increasing ID used
+// to correlate the log lines for a single request.
+func nextRequestID() string {
+	return strconv.FormatUint(requestIDCounter.Add(1), 10)
+}
@@ -10,8 +10,6 @@ import (
 	"log"
 	"net"
 	"net/http"
-	"net/http/httputil"
-	"net/url"
 	"os"
 	"os/signal"
 	"strconv"
@@ -25,6 +23,7 @@ import (
 const (
 	beaconEndpointDefault = "http://127.0.0.1:5052"
 	portDefault           = 3600
+	adminPortDefault      = 3601
 	secondsPerSlot        = 12
 	slotsPerEpoch         = 32
 
@@ -39,26 +38,91 @@ var (
 	slot0Timestamp   uint64
 	retentionPeriod  uint64
 	port             int
-	beaconEndpoint   string
+	adminPort        int
+	beaconEndpoints  string
+	beaconPool       *upstreamPool
+	fetchAllSidecars bool
+	elEndpoint       string
+	engineEndpoint   string
+	engineJWTSecret  string
+	engine           *engineClient
+	archiveBackend   string
+	archiveDir       string
+	archiveS3        s3Config
+	blobStore        BlobStore
 	emptySidecarList = &struct {
 		Data []interface{} `json:"data"`
 	}{Data: []interface{}{}}
 )
 
-func init() {
+func parseFlags() {
 	flag.Uint64Var(&retentionPeriod, "r", retentionPeriodDefault, "blob retention period in epochs")
 	flag.IntVar(&port, "p", portDefault, "listening port")
-	flag.StringVar(&beaconEndpoint, "b", beaconEndpointDefault, "beacon endpoint")
+	flag.IntVar(&adminPort, "admin.p", adminPortDefault, "admin listening port, serving /metrics")
+	flag.StringVar(&beaconEndpoints, "b", beaconEndpointDefault, "beacon endpoint, or a comma-separated list for failover")
+	flag.BoolVar(&fetchAllSidecars, "l1.beacon.fetch-all-sidecars", false, "always request the full sidecar set from upstream, ignoring indices, and filter locally")
+	flag.StringVar(&elEndpoint, "el", "", "execution layer JSON-RPC endpoint; when set (with -engine/-engine.jwtsecret), slots outside the retention window that aren't in the persistent archive (-archive.backend) are best-effort reconstructed via engine_getBlobsV1 instead of returning an empty list. This only works while the EL still has the blobs in its local blob pool, which is typically a much shorter window than the archive's retention -- it is not a substitute for -archive.backend as a true historical data source")
+	flag.StringVar(&engineEndpoint, "engine", "", "execution layer Engine API endpoint; when set (with -engine.jwtsecret, and -el for the eth_getBlockByNumber lookup of the block's versioned hashes), used to fetch blob contents via engine_getBlobsV1, both as a fallback for in-window slots the upstream beacon returns empty and for best-effort reconstruction of slots outside the retention window; blobs are only found while they are still in the EL's own blob pool")
+	flag.StringVar(&engineJWTSecret, "engine.jwtsecret", "", "path to the Engine API JWT secret file")
+	flag.StringVar(&archiveBackend, "archive.backend", "", "persistent blob archive backend: \"fs\", \"s3\", or empty to disable")
+	flag.StringVar(&archiveDir, "archive.dir", "", "directory for the fs archive backend")
+	flag.StringVar(&archiveS3.endpoint, "archive.s3.endpoint", "", "S3-compatible endpoint for the s3 archive backend")
+	flag.StringVar(&archiveS3.region, "archive.s3.region", "us-east-1", "region for the s3 archive backend")
+	flag.StringVar(&archiveS3.bucket, "archive.s3.bucket", "", "bucket for the s3 archive backend")
+	flag.StringVar(&archiveS3.accessKey, "archive.s3.access-key", "", "access key for the s3 archive backend")
+	flag.StringVar(&archiveS3.secretKey, "archive.s3.secret-key", "", "secret key for the s3 archive backend")
 	flag.Parse()
+
+	pool, err := newUpstreamPool(beaconEndpoints)
+	if err != nil {
+		log.Fatalf("Invalid beacon endpoint(s): %v", err)
+	}
+	beaconPool = pool
+}
+
+func newBlobStore() (BlobStore, error) {
+	switch archiveBackend {
+	case "":
+		return nil, nil
+	case "fs":
+		return newFSBlobStore(archiveDir)
+	case "s3":
+		return newS3BlobStore(archiveS3)
+	default:
+		return nil, fmt.Errorf("unknown archive backend %q", archiveBackend)
+	}
 }
 
 func main() {
+	parseFlags()
+	go startAdminServer(adminPort)
+
 	slot0Timestamp = queryGenesisTime()
-	targetURL, _ := url.Parse(beaconEndpoint)
+	queryMaxBlobsPerBlock()
+
+	store, err := newBlobStore()
+	if err != nil {
+		log.Fatalf("Failed to initialize blob archive: %v", err)
+	}
+	blobStore = store
+	if blobStore != nil {
+		logger.Info("Persistent blob archive enabled", "backend", archiveBackend)
+		go runArchiver(blobStore)
+	}
+
+	if engineEndpoint != "" {
+		client, err := newEngineClient(engineEndpoint, engineJWTSecret)
+		if err != nil {
+			log.Fatalf("Failed to initialize engine client: %v", err)
+		}
+		engine = client
+		logger.Info("Engine API fallback enabled", "engine_endpoint", engineEndpoint)
+	}
+
 	r := mux.NewRouter()
-	r.HandleFunc(versionMethod, createReverseProxy(targetURL))
-	r.HandleFunc(specMethod, createReverseProxy(targetURL))
-	r.HandleFunc(genesisMethod, createReverseProxy(targetURL))
+	r.HandleFunc(versionMethod, loggedProxy)
+	r.HandleFunc(specMethod, loggedProxy)
+	r.HandleFunc(genesisMethod, loggedProxy)
 	r.HandleFunc(sidecarsMethod, handleBlobSidecarsRequest)
 
 	server := &http.Server{
@@ -74,63 +138,183 @@ func main() {
 			log.Fatal(err)
 		}
 	}()
-	log.Printf("Beacon API wrapper started on %s\n", listener.Addr().String())
-	log.Printf("Beacon endpoint: %s\n", beaconEndpoint)
-	log.Printf("Retaining blobs for %d epochs (%d slots) \n", retentionPeriod, retentionPeriod*slotsPerEpoch)
+	logger.Info("Beacon API wrapper started", "endpoint", listener.Addr().String())
+	logger.Info("Beacon endpoint(s)", "endpoints", beaconEndpoints)
+	logger.Info("Retaining blobs", "epochs", retentionPeriod, "slots", retentionPeriod*slotsPerEpoch)
+	if elEndpoint != "" {
+		logger.Info("EL reconstruction enabled for expired slots not found in the archive (best-effort, limited by the EL's own blob pool retention)", "el_endpoint", elEndpoint, "engine_endpoint", engineEndpoint)
+	}
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
-	log.Println("Shutting down server...")
+	logger.Info("Shutting down server...")
 
 	if err := server.Shutdown(context.Background()); err != nil {
 		log.Fatalf("Server shutdown failed:%+v", err)
 	}
-	log.Println("Server exiting")
+	logger.Info("Server exiting")
 }
 
 func handleBlobSidecarsRequest(w http.ResponseWriter, r *http.Request) {
-	log.Printf("Received request for %s\n", r.URL.Path)
+	reqID := nextRequestID()
+	start := time.Now()
+	requestsTotal.WithLabelValues("blob_sidecars").Inc()
+
+	var (
+		slot, age         uint64
+		outcome, upstream string
+		status            = http.StatusOK
+	)
+	defer func() {
+		logger.Info("blob_sidecars request",
+			"request_id", reqID,
+			"path", r.URL.Path,
+			"slot", slot,
+			"age", age,
+			"outcome", outcome,
+			"upstream", upstream,
+			"status", status,
+			"duration", time.Since(start),
+		)
+	}()
 
 	id := mux.Vars(r)["id"]
-	if isHash(id) {
-		http.Error(w, "Block hash is not supported yet", http.StatusInternalServerError)
+	indices, err := parseIndices(r)
+	if err != nil {
+		status = http.StatusBadRequest
+		writeSpecError(w, status, err.Error())
 		return
 	}
-	if isKnownIdentifier(id) {
-		http.Error(w, fmt.Sprintf("%s is not supported yet", id), http.StatusInternalServerError)
+	slot, err = resolveSlot(id)
+	if err != nil {
+		status = http.StatusBadRequest
+		http.Error(w, "Invalid block ID", status)
 		return
 	}
-	age, err := slotAge(id)
+	age, err = slotAge(slot)
 	if err != nil {
-		http.Error(w, "Invalid block ID", http.StatusBadRequest)
+		status = http.StatusBadRequest
+		http.Error(w, "Invalid block ID", status)
 		return
 	}
-	// if block is not in the retention window  return 200 w/ empty list
+	// if block is not in the retention window, serve it from the archive
+	// or reconstruct it from the EL; otherwise fall back to 200 w/ empty list
 	// refer to https://github.com/prysmaticlabs/prysm/blob/feb16ae4aaa41d9bcd066b54b779dcd38fc928d2/beacon-chain/rpc/lookup/blocker.go#L226C20-L226C41
 	if age > retentionPeriod*slotsPerEpoch {
+		if blobStore != nil && blobStore.Has(slot) {
+			outcome = "archived"
+			retentionOutcomeTotal.WithLabelValues(outcome).Inc()
+			sidecars, err := blobStore.Get(slot, indices)
+			if err != nil {
+				status = http.StatusInternalServerError
+				logger.Error("Failed to read archived sidecars", "request_id", reqID, "slot", slot, "err", err)
+				http.Error(w, "Failed to read archived blob sidecars", status)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(&APIGetBlobSidecarsResponse{Data: sidecars})
+			return
+		}
+		if elEndpoint != "" {
+			outcome = "reconstructed"
+			retentionOutcomeTotal.WithLabelValues(outcome).Inc()
+			reconstructStart := time.Now()
+			sidecars, err := reconstructSidecars(slot)
+			reconstructionDuration.Observe(time.Since(reconstructStart).Seconds())
+			if err != nil {
+				status = http.StatusInternalServerError
+				logger.Error("Failed to reconstruct blob sidecars", "request_id", reqID, "slot", slot, "err", err)
+				http.Error(w, "Failed to reconstruct blob sidecars", status)
+				return
+			}
+			sidecars = filterSidecarsByIndices(sidecars, indices)
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(&APIGetBlobSidecarsResponse{Data: sidecars})
+			return
+		}
+		outcome = "empty"
+		retentionOutcomeTotal.WithLabelValues(outcome).Inc()
 		w.Header().Set("Content-Type", "application/json")
-		log.Printf("Block %s is not in the retention window\n", id)
 		json.NewEncoder(w).Encode(emptySidecarList)
 		return
 	}
-	targetURL, _ := url.Parse(beaconEndpoint)
-	httputil.NewSingleHostReverseProxy(targetURL).ServeHTTP(w, r)
-}
 
-func createReverseProxy(targetURL *url.URL) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		log.Printf("Received request for %s\n", r.URL.Path)
-		httputil.NewSingleHostReverseProxy(targetURL).ServeHTTP(w, r)
+	outcome = "proxied"
+	if fetchAllSidecars {
+		sidecars, err := fetchSidecarsFromUpstream(slot)
+		if err != nil {
+			status = http.StatusBadGateway
+			retentionOutcomeTotal.WithLabelValues(outcome).Inc()
+			logger.Error("Failed to fetch blob sidecars", "request_id", reqID, "slot", slot, "err", err)
+			http.Error(w, "Failed to fetch blob sidecars", status)
+			return
+		}
+		if len(sidecars) == 0 && engine != nil {
+			if fromEngine, err := engine.reconstructFromEngine(slot); err != nil {
+				logger.Error("Engine API fallback failed", "request_id", reqID, "slot", slot, "err", err)
+			} else if len(fromEngine) > 0 {
+				outcome = "engine_fallback"
+				sidecars = fromEngine
+			}
+		}
+		retentionOutcomeTotal.WithLabelValues(outcome).Inc()
+		sidecars = filterSidecarsByIndices(sidecars, indices)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(&APIGetBlobSidecarsResponse{Data: sidecars})
+		return
 	}
-}
 
-func slotAge(id string) (uint64, error) {
-	slot, err := strconv.ParseUint(id, 10, 64)
-	if err != nil {
-		return 0, err
+	if engine == nil {
+		upstream, status = beaconPool.ReverseProxy(w, r)
+		retentionOutcomeTotal.WithLabelValues(outcome).Inc()
+		return
 	}
 
+	// Proxy normally first, preserving the upstream's response as-is, and
+	// only fall back to the engine if upstream came back with no sidecars
+	// at all (e.g. it's still syncing or already pruned this slot).
+	buf, servedBy := beaconPool.reverseProxyBuffered(r)
+	if buf == nil {
+		status = http.StatusBadGateway
+		retentionOutcomeTotal.WithLabelValues(outcome).Inc()
+		http.Error(w, "all upstream beacon nodes failed", status)
+		return
+	}
+	upstream = servedBy
+	status = buf.status
+	var upstreamResponse APIGetBlobSidecarsResponse
+	if buf.status == http.StatusOK && json.Unmarshal(buf.body.Bytes(), &upstreamResponse) == nil && len(upstreamResponse.Data) == 0 {
+		if fromEngine, err := engine.reconstructFromEngine(slot); err != nil {
+			logger.Error("Engine API fallback failed", "request_id", reqID, "slot", slot, "err", err)
+		} else if len(fromEngine) > 0 {
+			outcome = "engine_fallback"
+			retentionOutcomeTotal.WithLabelValues(outcome).Inc()
+			sidecars := filterSidecarsByIndices(fromEngine, indices)
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(&APIGetBlobSidecarsResponse{Data: sidecars})
+			return
+		}
+	}
+	retentionOutcomeTotal.WithLabelValues(outcome).Inc()
+	buf.copyTo(w)
+}
+
+func loggedProxy(w http.ResponseWriter, r *http.Request) {
+	reqID := nextRequestID()
+	start := time.Now()
+	requestsTotal.WithLabelValues(r.URL.Path).Inc()
+	upstream, status := beaconPool.ReverseProxy(w, r)
+	logger.Info("proxy request",
+		"request_id", reqID,
+		"path", r.URL.Path,
+		"upstream", upstream,
+		"status", status,
+		"duration", time.Since(start),
+	)
+}
+
+func slotAge(slot uint64) (uint64, error) {
 	curSlot := (uint64(time.Now().Unix()) - slot0Timestamp) / secondsPerSlot
 	if curSlot < slot {
 		return 0, errors.New("querying a future slot")
@@ -164,7 +348,7 @@ type GenesisResponse struct {
 }
 
 func queryGenesisTime() uint64 {
-	resp, err := http.Get(beaconEndpoint + genesisMethod)
+	resp, err := beaconPool.Get(genesisMethod)
 	if err != nil {
 		log.Fatalf("Error fetching data: %v", err)
 	}
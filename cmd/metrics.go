@@ -0,0 +1,52 @@
+package main
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "beacon_wrapper_requests_total",
+		Help: "Total requests received, by endpoint.",
+	}, []string{"endpoint"})
+
+	retentionOutcomeTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "beacon_wrapper_retention_outcome_total",
+		Help: "Blob sidecar requests by how they were served: proxied (inside the retention window), engine_fallback (inside the retention window, from engine_getBlobsV1), archived, reconstructed, or empty.",
+	}, []string{"outcome"})
+
+	upstreamRequestDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name: "beacon_wrapper_upstream_request_duration_seconds",
+		Help: "Latency of requests to upstream beacon endpoints.",
+	})
+
+	upstreamFailuresTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "beacon_wrapper_upstream_failures_total",
+		Help: "Total upstream beacon requests that errored or returned 5xx.",
+	})
+
+	reconstructionDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name: "beacon_wrapper_reconstruction_duration_seconds",
+		Help: "Time spent reconstructing blob sidecars from the execution layer.",
+	})
+)
+
+// startAdminServer exposes /metrics on its own port, separate from the
+// wrapper's main listener, so scraping it doesn't compete with request
+// traffic.
+func startAdminServer(port int) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	endpoint := net.JoinHostPort("0.0.0.0", strconv.Itoa(port))
+	logger.Info("Starting admin server", "endpoint", endpoint)
+	if err := http.ListenAndServe(endpoint, mux); err != nil {
+		log.Fatalf("Admin server failed: %v", err)
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	blobTxType        = "0x3"
+	elBlockScanRadius = 8
+)
+
+// elRequest/elResponse model a JSON-RPC 2.0 call against the execution
+// layer: elCall sends it unauthenticated, engineClient.call with a JWT.
+type elRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      int           `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type elResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// elBlock is the subset of `eth_getBlockByNumber` (full-tx form) that
+// reconstruction needs.
+type elBlock struct {
+	Number       string `json:"number"`
+	Timestamp    string `json:"timestamp"`
+	Transactions []elTx `json:"transactions"`
+}
+
+// elTx is the subset of a JSON-RPC transaction object reconstruction needs.
+// Note there is no "blobs" field here: eth_getBlockByNumber only ever
+// serializes blobVersionedHashes for type-3 transactions, never the blob
+// bytes themselves, so the actual blob contents have to come from
+// somewhere that still stores them (see reconstructSidecars).
+type elTx struct {
+	Type                string   `json:"type"`
+	BlobVersionedHashes []string `json:"blobVersionedHashes"`
+}
+
+func elCall(method string, params ...interface{}) (json.RawMessage, error) {
+	body, err := json.Marshal(elRequest{JSONRPC: "2.0", ID: 1, Method: method, Params: params})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.Post(elEndpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var rpcResp elResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return nil, err
+	}
+	if rpcResp.Error != nil {
+		return nil, fmt.Errorf("%s: %s", method, rpcResp.Error.Message)
+	}
+	return rpcResp.Result, nil
+}
+
+func getBlockByNumber(number uint64) (*elBlock, error) {
+	raw, err := elCall("eth_getBlockByNumber", "0x"+strconv.FormatUint(number, 16), true)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil || string(raw) == "null" {
+		return nil, nil
+	}
+	block := new(elBlock)
+	if err := json.Unmarshal(raw, block); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// findBlockForSlot locates the EL block produced for the given slot by
+// scanning around the block number implied by the slot's timestamp: EL
+// block numbers don't map 1:1 to slots (missed slots shift the offset), so
+// we probe a small window centered on the estimate and match on timestamp.
+func findBlockForSlot(slot uint64) (*elBlock, error) {
+	slotTimestamp := slot0Timestamp + slot*secondsPerSlot
+	estimate := slot
+	for delta := -elBlockScanRadius; delta <= elBlockScanRadius; delta++ {
+		if int64(estimate)+int64(delta) < 0 {
+			continue
+		}
+		number := uint64(int64(estimate) + int64(delta))
+		block, err := getBlockByNumber(number)
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			continue
+		}
+		timestamp, err := strconv.ParseUint(strings.TrimPrefix(block.Timestamp, "0x"), 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		if timestamp == slotTimestamp {
+			return block, nil
+		}
+	}
+	return nil, fmt.Errorf("no EL block found for slot %d", slot)
+}
+
+// reconstructSidecars is the last-resort path for a slot that has aged out
+// of the beacon's retention window and isn't in the persistent archive
+// (blobStore, if configured). eth_getBlockByNumber only ever gives us the
+// blob-carrying transactions' versioned hashes, never the blob bytes
+// themselves (go-ethereum's RPCTransaction doesn't serialize a "blobs"
+// field), so the blob contents have to be fetched from the authenticated
+// Engine API via engine_getBlobsV1 instead, the same source chunk0-7's
+// in-window fallback uses. This is best-effort, not a historical data
+// source: engine_getBlobsV1 only returns blobs still sitting in the EL's
+// local transaction pool, which the EL prunes on its own schedule,
+// typically much sooner than this wrapper's own retention window expires.
+// Once a slot has aged out of both the archive and the EL's blob pool, its
+// sidecars are permanently unrecoverable here; the persistent archive
+// (-archive.backend) is the only durable backfill source this wrapper has.
+func reconstructSidecars(slot uint64) ([]*BlobSidecar, error) {
+	if engine == nil {
+		return nil, fmt.Errorf("reconstructing slot %d: -engine/-engine.jwtsecret must be configured to fetch blob contents via engine_getBlobsV1; eth_getBlockByNumber does not return blob bytes", slot)
+	}
+	sidecars, err := engine.reconstructFromEngine(slot)
+	if err != nil {
+		return nil, err
+	}
+	if len(sidecars) == 0 {
+		logger.Warn("No blob sidecars recovered for expired slot; likely aged out of both the archive and the EL's blob pool", "slot", slot)
+	} else {
+		logger.Info("Reconstructed blob sidecars from EL", "slot", slot, "count", len(sidecars))
+	}
+	return sidecars, nil
+}
+
+func hexToBytes(s string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
+
+func bytesToHex(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
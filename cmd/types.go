@@ -0,0 +1,44 @@
+package main
+
+// SignedBeaconBlockHeader mirrors the `signed_block_header` object returned
+// by the beacon API for a blob sidecar.
+type SignedBeaconBlockHeader struct {
+	Message   *BeaconBlockHeader `json:"message"`
+	Signature string             `json:"signature"`
+}
+
+// BeaconBlockHeader mirrors the `message` object inside a
+// SignedBeaconBlockHeader.
+type BeaconBlockHeader struct {
+	Slot          string `json:"slot"`
+	ProposerIndex string `json:"proposer_index"`
+	ParentRoot    string `json:"parent_root"`
+	StateRoot     string `json:"state_root"`
+	BodyRoot      string `json:"body_root"`
+}
+
+// BlobSidecar mirrors a single entry of the `data` array returned by
+// GET /eth/v1/beacon/blob_sidecars/{id}.
+//
+// KZGCommitmentInclusionProof is spec-required and normally a
+// KZG_COMMITMENT_INCLUSION_PROOF_DEPTH-length array of Merkle sibling
+// hashes. This wrapper cannot compute a real one for EL-reconstructed
+// sidecars (see engineClient.reconstructFromEngine) since that needs the
+// full BeaconBlockBody container, not just the commitments list, so those
+// sidecars instead set it to an explicit empty slice rather than leaving it
+// null -- it's still not spec-valid, but an empty array is a smaller
+// surprise for a strict consumer than a missing required field.
+type BlobSidecar struct {
+	Index                       string                   `json:"index"`
+	Blob                        string                   `json:"blob"`
+	KZGCommitment               string                   `json:"kzg_commitment"`
+	KZGProof                    string                   `json:"kzg_proof"`
+	SignedBlockHeader           *SignedBeaconBlockHeader `json:"signed_block_header"`
+	KZGCommitmentInclusionProof []string                 `json:"kzg_commitment_inclusion_proof"`
+}
+
+// APIGetBlobSidecarsResponse is the response body of
+// GET /eth/v1/beacon/blob_sidecars/{id}.
+type APIGetBlobSidecarsResponse struct {
+	Data []*BlobSidecar `json:"data"`
+}
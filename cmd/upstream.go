@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	backoffBase = 1 * time.Second
+	backoffMax  = 30 * time.Second
+	// backoffMaxShift is the largest exponent recordFailure will shift
+	// backoffBase by. backoffBase<<34 already overflows time.Duration
+	// (int64) into a negative number, so the exponent must be clamped well
+	// before that; backoffMax saturates the result long before this shift
+	// count is reached anyway.
+	backoffMaxShift = 5
+)
+
+// upstreamBeacon tracks the health of a single beacon endpoint so the pool
+// can skip it while it's backing off.
+type upstreamBeacon struct {
+	url *url.URL
+
+	mu                  sync.Mutex
+	consecutiveFailures int
+	backoffUntil        time.Time
+}
+
+func (u *upstreamBeacon) healthy(now time.Time) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return now.After(u.backoffUntil)
+}
+
+func (u *upstreamBeacon) recordSuccess() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.consecutiveFailures = 0
+	u.backoffUntil = time.Time{}
+}
+
+func (u *upstreamBeacon) recordFailure() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.consecutiveFailures++
+	shift := u.consecutiveFailures - 1
+	if shift > backoffMaxShift {
+		shift = backoffMaxShift
+	}
+	backoff := backoffBase << uint(shift)
+	if backoff > backoffMax {
+		backoff = backoffMax
+	}
+	u.backoffUntil = time.Now().Add(backoff)
+}
+
+// upstreamPool is a set of beacon endpoints tried in order, with failover
+// on connection errors and 5xx responses. Each endpoint's health is
+// tracked independently with exponential backoff.
+type upstreamPool struct {
+	upstreams []*upstreamBeacon
+}
+
+func newUpstreamPool(endpoints string) (*upstreamPool, error) {
+	var upstreams []*upstreamBeacon
+	for _, endpoint := range strings.Split(endpoints, ",") {
+		endpoint = strings.TrimSpace(endpoint)
+		if endpoint == "" {
+			continue
+		}
+		parsed, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("parsing beacon endpoint %q: %w", endpoint, err)
+		}
+		upstreams = append(upstreams, &upstreamBeacon{url: parsed})
+	}
+	if len(upstreams) == 0 {
+		return nil, fmt.Errorf("no beacon endpoints configured")
+	}
+	return &upstreamPool{upstreams: upstreams}, nil
+}
+
+// ordered returns the healthy upstreams in configured order, leaving out
+// those that are backing off; if every upstream is backing off, all are
+// returned anyway so a
+// request doesn't hard-fail just because the backoff windows haven't
+// cleared yet.
+func (p *upstreamPool) ordered() []*upstreamBeacon {
+	now := time.Now()
+	var healthy, backingOff []*upstreamBeacon
+	for _, u := range p.upstreams {
+		if u.healthy(now) {
+			healthy = append(healthy, u)
+		} else {
+			backingOff = append(backingOff, u)
+		}
+	}
+	if len(healthy) == 0 {
+		return backingOff
+	}
+	return healthy
+}
+
+// Get performs an HTTP GET against path, trying each upstream in order and
+// failing over to the next on a connection error or 5xx response.
+func (p *upstreamPool) Get(path string) (*http.Response, error) {
+	var lastErr error
+	for _, u := range p.ordered() {
+		start := time.Now()
+		resp, err := http.Get(u.url.String() + path)
+		upstreamRequestDuration.Observe(time.Since(start).Seconds())
+		if err != nil {
+			u.recordFailure()
+			upstreamFailuresTotal.Inc()
+			lastErr = err
+			continue
+		}
+		if resp.StatusCode >= http.StatusInternalServerError {
+			resp.Body.Close()
+			u.recordFailure()
+			upstreamFailuresTotal.Inc()
+			lastErr = fmt.Errorf("%s returned %s", u.url, resp.Status)
+			continue
+		}
+		u.recordSuccess()
+		return resp, nil
+	}
+	return nil, fmt.Errorf("all upstream beacon nodes failed for %s: %w", path, lastErr)
+}
+
+// ReverseProxy proxies r to the first healthy upstream that returns a
+// non-5xx response, buffering each attempt so a failed one never reaches
+// the real client. It returns the upstream that served the response (or
+// "" if every upstream failed) and the status code returned to the
+// client, for the caller's request log.
+func (p *upstreamPool) ReverseProxy(w http.ResponseWriter, r *http.Request) (servedBy string, status int) {
+	buf, servedBy := p.reverseProxyBuffered(r)
+	if buf == nil {
+		http.Error(w, "all upstream beacon nodes failed", http.StatusBadGateway)
+		return "", http.StatusBadGateway
+	}
+	buf.copyTo(w)
+	return servedBy, buf.status
+}
+
+// reverseProxyBuffered is ReverseProxy without the final write to the real
+// client, so a caller can inspect the buffered response (e.g. to decide
+// whether a fallback is needed) before deciding what to send back. It
+// returns a nil buffer if every upstream failed.
+func (p *upstreamPool) reverseProxyBuffered(r *http.Request) (*bufferedResponse, string) {
+	for _, u := range p.ordered() {
+		buf := newBufferedResponse()
+		start := time.Now()
+		httputil.NewSingleHostReverseProxy(u.url).ServeHTTP(buf, r.Clone(r.Context()))
+		upstreamRequestDuration.Observe(time.Since(start).Seconds())
+		if buf.status < http.StatusInternalServerError {
+			u.recordSuccess()
+			return buf, u.url.String()
+		}
+		u.recordFailure()
+		upstreamFailuresTotal.Inc()
+	}
+	return nil, ""
+}
+
+// bufferedResponse is a minimal http.ResponseWriter that captures a
+// response instead of writing it to the network, so ReverseProxy can
+// inspect the status code before committing it to the real client.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBufferedResponse() *bufferedResponse {
+	return &bufferedResponse{header: make(http.Header), status: http.StatusOK}
+}
+
+func (b *bufferedResponse) Header() http.Header { return b.header }
+
+func (b *bufferedResponse) Write(p []byte) (int, error) { return b.body.Write(p) }
+
+func (b *bufferedResponse) WriteHeader(status int) { b.status = status }
+
+func (b *bufferedResponse) copyTo(w http.ResponseWriter) {
+	for key, values := range b.header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	w.WriteHeader(b.status)
+	w.Write(b.body.Bytes())
+}
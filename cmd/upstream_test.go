@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecordFailureCapsAtBackoffMax(t *testing.T) {
+	u := &upstreamBeacon{}
+	for i := 0; i < 40; i++ {
+		u.recordFailure()
+	}
+	backoff := time.Until(u.backoffUntil)
+	if backoff > backoffMax {
+		t.Fatalf("backoff %v exceeds backoffMax %v after %d failures", backoff, backoffMax, u.consecutiveFailures)
+	}
+	if backoff <= 0 {
+		t.Fatalf("backoff is non-positive after %d failures, endpoint would be reported healthy again", u.consecutiveFailures)
+	}
+}
+
+func TestRecordSuccessResetsBackoff(t *testing.T) {
+	u := &upstreamBeacon{}
+	u.recordFailure()
+	u.recordFailure()
+	u.recordSuccess()
+	if u.consecutiveFailures != 0 {
+		t.Fatalf("consecutiveFailures = %d, want 0", u.consecutiveFailures)
+	}
+	if !u.healthy(time.Now()) {
+		t.Fatalf("endpoint should be healthy immediately after recordSuccess")
+	}
+}